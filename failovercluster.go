@@ -0,0 +1,250 @@
+package radix
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mediocregopher/radix/v4/resp"
+	"github.com/mediocregopher/radix/v4/resp/resp3"
+)
+
+// FailoverCluster wraps a Sentinel to provide per-command routing similar to
+// a real Redis Cluster client, on top of a classic Sentinel
+// primary/replica(s) deployment: write commands (and any command FailoverCluster
+// can't otherwise classify) are routed to the primary, read-only commands are
+// routed to a replica, and repeated reads for the same key are pinned to the
+// same replica.
+//
+// FailoverCluster reuses its underlying Sentinel's connections and topology
+// tracking; it does not dial anything on its own.
+type FailoverCluster struct {
+	sc *Sentinel
+}
+
+var _ MultiClient = new(FailoverCluster)
+
+// NewFailoverCluster creates a Sentinel using the given parameters (see
+// NewSentinel for the meaning of each) and wraps it in a FailoverCluster.
+func NewFailoverCluster(
+	ctx context.Context, primaryName string, sentinelAddrs []string, opts ...SentinelOpt,
+) (*FailoverCluster, error) {
+	sc, err := NewSentinel(ctx, primaryName, sentinelAddrs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &FailoverCluster{sc: sc}, nil
+}
+
+// Do implements the method for the Client interface. Commands classified as
+// read-only (see failoverClusterReadCmds) are routed to a replica: if the
+// command has a key, the replica is chosen by hashing that key (using the
+// same CRC16 algorithm, including hash-tag support, that Redis Cluster uses
+// for slot assignment) against the sorted list of currently known replicas,
+// so that repeated reads of the same key land on the same replica; if it has
+// no key, a replica is chosen using the Sentinel's configured
+// ReplicaSelector. Everything else -- writes, commands with no classification,
+// and reads when no replica is available -- is routed to the primary.
+func (fc *FailoverCluster) Do(ctx context.Context, a Action) error {
+	verb, key := inspectAction(a)
+	// the primary is used directly rather than via Sentinel.Do, which would
+	// send everything to a replica under SentinelReplicaOnly.
+	doPrimary := func() error {
+		return fc.sc.proc.WithRLock(func() error {
+			return fc.sc.clients[fc.sc.primAddr].Do(ctx, a)
+		})
+	}
+	if !failoverClusterReadCmds[verb] {
+		return doPrimary()
+	}
+
+	replicaAddrs, err := fc.sortedReplicaAddrs()
+	if err != nil {
+		return err
+	} else if len(replicaAddrs) == 0 {
+		return doPrimary()
+	}
+
+	var addr string
+	if key != "" {
+		addr = replicaAddrs[crc16sum(hashTagKey(key))%uint16(len(replicaAddrs))]
+	} else {
+		addr = fc.sc.opts.replicaSelector.selectReplica(replicaAddrs)
+	}
+	if addr == "" {
+		return doPrimary()
+	}
+
+	c, err := fc.sc.client(ctx, addr)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err = c.Do(ctx, a)
+	fc.sc.opts.replicaSelector.sample(addr, time.Since(start), err)
+	return err
+}
+
+// Clients implements the method for the MultiClient interface, and simply
+// defers to the underlying Sentinel's.
+func (fc *FailoverCluster) Clients() (map[string]ReplicaSet, error) {
+	return fc.sc.Clients()
+}
+
+// Close implements the method for the Client interface, and closes the
+// underlying Sentinel.
+func (fc *FailoverCluster) Close() error {
+	return fc.sc.Close()
+}
+
+// sortedReplicaAddrs returns the addresses of all currently known replicas,
+// in sorted order, so that hashing a key against this list gives a
+// consistent result across calls as long as the set of replicas hasn't
+// changed. It's recomputed fresh under the Sentinel's own lock on every call,
+// so a switch-master (or replica addition/removal) is always reflected on
+// the very next command.
+func (fc *FailoverCluster) sortedReplicaAddrs() ([]string, error) {
+	var addrs []string
+	err := fc.sc.proc.WithRLock(func() error {
+		addrs = make([]string, 0, len(fc.sc.clients))
+		for addr := range fc.sc.clients {
+			if addr != fc.sc.primAddr && !fc.sc.unhealthyReplicas[addr] && !fc.sc.disconnectedAddrs[addr] {
+				addrs = append(addrs, addr)
+			}
+		}
+		return nil
+	})
+	sort.Strings(addrs)
+	return addrs, err
+}
+
+// failoverClusterReadCmds is the set of command verbs which Do considers
+// read-only, and therefore eligible for routing to a replica. Anything not in
+// this set is routed to the primary.
+var failoverClusterReadCmds = map[string]bool{
+	"DUMP": true, "EXISTS": true, "GET": true, "GETRANGE": true, "GETBIT": true,
+	"HEXISTS": true, "HGET": true, "HGETALL": true, "HKEYS": true, "HLEN": true,
+	"HMGET": true, "HRANDFIELD": true, "HSCAN": true, "HSTRLEN": true, "HVALS": true,
+	"LINDEX": true, "LLEN": true, "LPOS": true, "LRANGE": true,
+	"MGET": true, "OBJECT": true, "PTTL": true, "RANDOMKEY": true,
+	"SCARD": true, "SDIFF": true, "SINTER": true, "SISMEMBER": true, "SMEMBERS": true,
+	"SMISMEMBER": true, "SRANDMEMBER": true, "SSCAN": true, "STRLEN": true, "SUNION": true,
+	"TTL": true, "TYPE": true,
+	"ZCARD": true, "ZCOUNT": true, "ZRANGE": true, "ZRANGEBYSCORE": true, "ZRANK": true,
+	"ZREVRANGE": true, "ZREVRANGEBYSCORE": true, "ZREVRANK": true, "ZSCAN": true, "ZSCORE": true,
+}
+
+// inspectAction returns the command verb (e.g. "GET") and key (if any) that a
+// would send, without actually performing it against any connection. It does
+// this by handing a a throwaway Conn which captures whatever a tries to
+// encode and then immediately errors out, the same way NewStubConn's
+// EncodeDecode decodes a command into a []string for its callback.
+//
+// For most commands the key is the first argument, but a few commands in
+// failoverClusterReadCmds are containers whose first argument is a
+// subcommand rather than a key (e.g. "OBJECT ENCODING key"); those are
+// special-cased in containerCmdKeyIdx so they still hash on the actual key
+// instead of the subcommand.
+func inspectAction(a Action) (verb string, key string) {
+	c := new(verbCaptureConn)
+	_ = a.Perform(context.Background(), c)
+	if len(c.ss) == 0 {
+		return "", ""
+	}
+	verb = strings.ToUpper(c.ss[0])
+
+	keyIdx := 1
+	if idx, ok := containerCmdKeyIdx[verb]; ok {
+		keyIdx = idx
+	}
+	if len(c.ss) > keyIdx {
+		key = c.ss[keyIdx]
+	}
+	return verb, key
+}
+
+// containerCmdKeyIdx overrides the argument index used as the key, for
+// commands in failoverClusterReadCmds whose key isn't their first argument.
+var containerCmdKeyIdx = map[string]int{
+	"OBJECT": 2, // OBJECT <subcommand> key
+}
+
+var errVerbCaptured = errors.New("radix: command verb captured")
+
+type verbCaptureConn struct {
+	ss []string
+}
+
+func (c *verbCaptureConn) Do(ctx context.Context, a Action) error {
+	return a.Perform(ctx, c)
+}
+
+func (c *verbCaptureConn) EncodeDecode(ctx context.Context, m, u interface{}) error {
+	if m == nil {
+		return errVerbCaptured
+	}
+
+	buf := new(bytes.Buffer)
+	if err := resp3.Marshal(buf, m, resp.NewOpts()); err != nil {
+		return err
+	}
+	_ = resp3.Unmarshal(bufio.NewReader(buf), &c.ss, resp.NewOpts())
+	return errVerbCaptured
+}
+
+func (c *verbCaptureConn) Close() error { return nil }
+
+func (c *verbCaptureConn) Addr() net.Addr { return nil }
+
+////////////////////////////////////////////////////////////////////////////////
+
+// hashTagKey returns the portion of key used for hashing, honoring Redis
+// Cluster's hash-tag syntax: if key contains a non-empty {...} substring, only
+// the contents of the braces are hashed, so that related keys can be forced
+// onto the same replica; otherwise the whole key is used.
+func hashTagKey(key string) string {
+	start := strings.IndexByte(key, '{')
+	if start < 0 {
+		return key
+	}
+	end := strings.IndexByte(key[start+1:], '}')
+	if end <= 0 {
+		return key
+	}
+	return key[start+1 : start+1+end]
+}
+
+var crc16Table = genCRC16Table()
+
+// genCRC16Table builds the lookup table for the CRC16/CCITT variant (with the
+// 0x1021 polynomial) that Redis Cluster uses to assign keys to hash slots.
+func genCRC16Table() [256]uint16 {
+	const poly = 0x1021
+	var tbl [256]uint16
+	for i := 0; i < 256; i++ {
+		crc := uint16(i) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = (crc << 1) ^ poly
+			} else {
+				crc <<= 1
+			}
+		}
+		tbl[i] = crc
+	}
+	return tbl
+}
+
+func crc16sum(key string) uint16 {
+	var crc uint16
+	for i := 0; i < len(key); i++ {
+		crc = (crc << 8) ^ crc16Table[byte(crc>>8)^key[i]]
+	}
+	return crc
+}
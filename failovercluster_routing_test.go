@@ -0,0 +1,87 @@
+package radix
+
+import (
+	"context"
+	"testing"
+)
+
+// recordingClient wraps a stub Conn/Client and appends every command verb it
+// sees (as sent by the real Action) to calls.
+func recordingClient(calls *[]string) Client {
+	return NewStubConn("", "", func(ss []string) interface{} {
+		if len(ss) > 0 {
+			*calls = append(*calls, ss[0])
+		}
+		return nil
+	})
+}
+
+func TestFailoverClusterDoRouting(t *testing.T) {
+	ctx := context.Background()
+
+	var primCalls, replicaACalls, replicaBCalls []string
+	sc := newTestSentinel()
+	sc.opts.replicaSelector = SelectRoundRobin()
+	sc.clients = map[string]Client{
+		"primary":  recordingClient(&primCalls),
+		"replicaA": recordingClient(&replicaACalls),
+		"replicaB": recordingClient(&replicaBCalls),
+	}
+	fc := &FailoverCluster{sc: sc}
+
+	// a write always goes to the primary.
+	if err := fc.Do(ctx, Cmd(nil, "SET", "foo", "bar")); err != nil {
+		t.Fatalf("Do(SET) err = %v", err)
+	}
+	if len(primCalls) != 1 || len(replicaACalls)+len(replicaBCalls) != 0 {
+		t.Fatalf("after SET: primCalls=%v replicaACalls=%v replicaBCalls=%v, want only primary called",
+			primCalls, replicaACalls, replicaBCalls)
+	}
+
+	// a read with a key is pinned to whichever replica the key hashes to,
+	// consistently across repeated calls.
+	for i := 0; i < 3; i++ {
+		if err := fc.Do(ctx, Cmd(nil, "GET", "foo")); err != nil {
+			t.Fatalf("Do(GET) err = %v", err)
+		}
+	}
+	aCount, bCount := len(replicaACalls), len(replicaBCalls)
+	if aCount+bCount != 3 || (aCount != 0 && bCount != 0) {
+		t.Fatalf("after 3x GET foo: replicaACalls=%v replicaBCalls=%v, want all 3 pinned to one replica",
+			replicaACalls, replicaBCalls)
+	}
+	if len(primCalls) != 1 {
+		t.Errorf("primCalls = %v, want untouched by the reads", primCalls)
+	}
+
+	// a read with no key falls back to the configured ReplicaSelector,
+	// rather than the primary.
+	replicaACalls, replicaBCalls = nil, nil
+	if err := fc.Do(ctx, Cmd(nil, "RANDOMKEY")); err != nil {
+		t.Fatalf("Do(RANDOMKEY) err = %v", err)
+	}
+	if len(replicaACalls)+len(replicaBCalls) != 1 {
+		t.Errorf("after RANDOMKEY: replicaACalls=%v replicaBCalls=%v, want exactly one replica called",
+			replicaACalls, replicaBCalls)
+	}
+	if len(primCalls) != 1 {
+		t.Errorf("primCalls = %v, want untouched by RANDOMKEY", primCalls)
+	}
+}
+
+func TestFailoverClusterDoFallsBackToPrimaryWithNoReplicas(t *testing.T) {
+	ctx := context.Background()
+
+	var primCalls []string
+	sc := newTestSentinel()
+	sc.opts.replicaSelector = SelectRandom()
+	sc.clients = map[string]Client{"primary": recordingClient(&primCalls)}
+	fc := &FailoverCluster{sc: sc}
+
+	if err := fc.Do(ctx, Cmd(nil, "GET", "foo")); err != nil {
+		t.Fatalf("Do(GET) err = %v", err)
+	}
+	if len(primCalls) != 1 {
+		t.Errorf("primCalls = %v, want the read routed to the primary when there are no replicas", primCalls)
+	}
+}
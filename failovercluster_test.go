@@ -0,0 +1,91 @@
+package radix
+
+import "testing"
+
+func TestHashTagKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "foo", want: "foo"},
+		{key: "{user1000}.following", want: "user1000"},
+		{key: "foo{}bar", want: "foo{}bar"}, // empty tag is ignored
+		{key: "{foo", want: "{foo"},         // unterminated tag is ignored
+		{key: "foo{bar}{baz}", want: "bar"}, // only the first tag counts
+	}
+
+	for _, test := range tests {
+		if got := hashTagKey(test.key); got != test.want {
+			t.Errorf("hashTagKey(%q) = %q, want %q", test.key, got, test.want)
+		}
+	}
+}
+
+func TestCRC16Sum(t *testing.T) {
+	// known CRC16/CCITT (0x1021) values for the Redis Cluster hash slot
+	// algorithm's standard test vectors.
+	tests := []struct {
+		key  string
+		want uint16
+	}{
+		{key: "", want: 0},
+		{key: "123456789", want: 0x31C3},
+	}
+
+	for _, test := range tests {
+		if got := crc16sum(test.key); got != test.want {
+			t.Errorf("crc16sum(%q) = %#x, want %#x", test.key, got, test.want)
+		}
+	}
+}
+
+func TestCRC16SumConsistentForHashTaggedKeys(t *testing.T) {
+	a := crc16sum(hashTagKey("{user1000}.following"))
+	b := crc16sum(hashTagKey("{user1000}.followers"))
+	if a != b {
+		t.Errorf("keys sharing a hash tag hashed differently: %#x != %#x", a, b)
+	}
+}
+
+func TestInspectAction(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        Action
+		wantVerb string
+		wantKey  string
+	}{
+		{
+			name:     "simple command",
+			a:        Cmd(nil, "GET", "foo"),
+			wantVerb: "GET",
+			wantKey:  "foo",
+		},
+		{
+			name:     "verb is uppercased",
+			a:        Cmd(nil, "get", "foo"),
+			wantVerb: "GET",
+			wantKey:  "foo",
+		},
+		{
+			name:     "no key",
+			a:        Cmd(nil, "RANDOMKEY"),
+			wantVerb: "RANDOMKEY",
+			wantKey:  "",
+		},
+		{
+			name:     "OBJECT takes its key from the third argument",
+			a:        Cmd(nil, "OBJECT", "ENCODING", "mykey"),
+			wantVerb: "OBJECT",
+			wantKey:  "mykey",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			verb, key := inspectAction(test.a)
+			if verb != test.wantVerb || key != test.wantKey {
+				t.Errorf("inspectAction() = (%q, %q), want (%q, %q)", verb, key, test.wantVerb, test.wantKey)
+			}
+		})
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"net"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -14,21 +16,40 @@ import (
 
 // wrapDefaultConnFunc is used to ensure that redis url options are
 // automatically applied to future sentinel connections whose address doesn't
-// have that information encoded.
-func wrapDefaultConnFunc(addr string) ConnFunc {
+// have that information encoded. extra, if given, is appended after the
+// options parsed from addr, so that it can override them (e.g. sentinel-only
+// auth credentials set via SentinelAuth/SentinelAuthACL).
+func wrapDefaultConnFunc(addr string, extra ...DialOpt) ConnFunc {
 	_, opts := parseRedisURL(addr)
+	opts = append(opts, extra...)
 	return func(ctx context.Context, network, addr string) (Conn, error) {
 		return Dial(ctx, network, addr, opts...)
 	}
 }
 
 type sentinelOpts struct {
-	cf    ConnFunc
-	pf    ClientFunc
-	st    trace.SentinelTrace
-	errCh chan<- error
+	cf                      ConnFunc
+	customConnFunc          bool
+	pf                      ClientFunc
+	customPoolFunc          bool
+	st                      trace.SentinelTrace
+	errCh                   chan<- error
+	replicaSelector         ReplicaSelector
+	replicaOnly             bool
+	useDisconnectedReplicas bool
+	authOpt                 DialOpt
+	authKind                string // "", "pass", or "acl"
+	authConflict            bool
+	clientName              string
+	pollInterval            time.Duration
 }
 
+// defaultSentinelPollInterval is used as the poll interval when
+// SentinelPollInterval isn't given. It's only a safety net for topology
+// changes missed by the event stream subscribed to in innerSpin; it keeps the
+// old hardcoded value of 5 seconds, and SentinelPollInterval can raise it.
+const defaultSentinelPollInterval = 5 * time.Second
+
 // SentinelOpt is an optional behavior which can be applied to the NewSentinel
 // function to effect a Sentinel's behavior.
 type SentinelOpt func(*sentinelOpts)
@@ -44,6 +65,7 @@ type SentinelOpt func(*sentinelOpts)
 func SentinelConnFunc(cf ConnFunc) SentinelOpt {
 	return func(so *sentinelOpts) {
 		so.cf = cf
+		so.customConnFunc = true
 	}
 }
 
@@ -52,6 +74,7 @@ func SentinelConnFunc(cf ConnFunc) SentinelOpt {
 func SentinelPoolFunc(pf ClientFunc) SentinelOpt {
 	return func(so *sentinelOpts) {
 		so.pf = pf
+		so.customPoolFunc = true
 	}
 }
 
@@ -67,12 +90,111 @@ func SentinelErrCh(errCh chan<- error) SentinelOpt {
 // SentinelWithTrace tells the Sentinel to trace itself with the given
 // SentinelTrace. Note that SentinelTrace will block at every point which is set
 // to trace.
+//
+// This package's Sentinel relies on two trace/ additions that ship alongside
+// it: a SentinelEvent hook on SentinelTrace (see handleSentinelEvent) and an
+// IsDisconnected field on SentinelNodeInfo (see ensureClients). Both are
+// additive and optional, like the rest of SentinelTrace.
 func SentinelWithTrace(st trace.SentinelTrace) SentinelOpt {
 	return func(so *sentinelOpts) {
 		so.st = st
 	}
 }
 
+// SentinelReplicaSelector tells the Sentinel which ReplicaSelector to use when
+// choosing a replica to service a DoSecondary call (or, if SentinelReplicaOnly
+// is used, a Do call). The default is SelectRandom.
+func SentinelReplicaSelector(rs ReplicaSelector) SentinelOpt {
+	return func(so *sentinelOpts) {
+		so.replicaSelector = rs
+	}
+}
+
+// SentinelReplicaOnly tells the Sentinel to route Do calls to a replica,
+// chosen using the same ReplicaSelector as DoSecondary, instead of the
+// primary. This is useful for read-heavy workloads which never want to touch
+// the primary. It is safe to combine with SentinelUseDisconnectedReplicas.
+//
+// NOTE that this only affects Do; DoSecondary already routes to a replica.
+func SentinelReplicaOnly() SentinelOpt {
+	return func(so *sentinelOpts) {
+		so.replicaOnly = true
+	}
+}
+
+// SentinelAuth tells the Sentinel to authenticate with this password on every
+// sentinel connection it makes (i.e. not connections to the primary or
+// replicas), via the legacy single-argument AUTH command. This is useful when
+// the sentinels themselves are protected by a password different from the one
+// used by the primary/replicas. It has no effect if SentinelConnFunc is also
+// given; in that case DialAuthPass should be used within the given ConnFunc
+// instead.
+//
+// SentinelAuth and SentinelAuthACL are mutually exclusive.
+func SentinelAuth(password string) SentinelOpt {
+	return func(so *sentinelOpts) {
+		if so.authKind != "" && so.authKind != "pass" {
+			so.authConflict = true
+		}
+		so.authKind = "pass"
+		so.authOpt = DialAuthPass(password)
+	}
+}
+
+// SentinelAuthACL is like SentinelAuth, but authenticates using the given ACL
+// username/password pair (i.e. the two-argument form of AUTH) rather than the
+// legacy single-argument form.
+//
+// SentinelAuth and SentinelAuthACL are mutually exclusive.
+func SentinelAuthACL(username, password string) SentinelOpt {
+	return func(so *sentinelOpts) {
+		if so.authKind != "" && so.authKind != "acl" {
+			so.authConflict = true
+		}
+		so.authKind = "acl"
+		so.authOpt = DialAuthUser(username, password)
+	}
+}
+
+// SentinelClientName tells the Sentinel to issue a CLIENT SETNAME with the
+// given name on every connection it makes, to sentinels as well as to the
+// primary and replicas, immediately after authenticating. This applies to
+// every connection opened by a primary/replica pool, not just one of them,
+// unless SentinelPoolFunc is also used to supply a custom ClientFunc, in
+// which case only the first connection it hands out gets named.
+func SentinelClientName(name string) SentinelOpt {
+	return func(so *sentinelOpts) {
+		so.clientName = name
+	}
+}
+
+// SentinelUseDisconnectedReplicas tells the Sentinel to keep track of
+// replicas which the sentinels report as being down (their SENTINEL SLAVES
+// flags contain s_down, o_down, or disconnected) separately from healthy
+// ones. If a DoSecondary call (or a Do call under SentinelReplicaOnly) can't
+// find a healthy replica to use, the Sentinel will fall back to opening a
+// Client against one of these disconnected replicas rather than falling back
+// to the primary.
+func SentinelUseDisconnectedReplicas() SentinelOpt {
+	return func(so *sentinelOpts) {
+		so.useDisconnectedReplicas = true
+	}
+}
+
+// SentinelPollInterval tells the Sentinel how often innerSpin should poll the
+// sentinels for their view of the primary/replicas, as a safety net in case
+// the event stream it's subscribed to misses something. The default is 5
+// seconds; since most topology changes are now picked up immediately off of
+// that event stream, larger fleets may want to raise this to cut down on
+// needless SENTINEL MASTER/SLAVES traffic. The interval is not the only
+// trigger: innerSpin also polls after every event it receives and, when
+// SelectByLatency is in use, after every replica latency probe.
+func SentinelPollInterval(d time.Duration) SentinelOpt {
+	return func(so *sentinelOpts) {
+		so.pollInterval = d
+	}
+}
+
 // Sentinel is a Client which, in the background, connects to an available
 // sentinel node and handles all of the following:
 //
@@ -96,6 +218,20 @@ type Sentinel struct {
 	clients       map[string]Client
 	sentinelAddrs map[string]bool // the known sentinel addresses
 
+	// disconnectedAddrs holds the addresses of replicas which the sentinels
+	// are currently reporting as down (s_down/o_down/disconnected). Clients
+	// for these addresses are only ever created lazily, as a last resort
+	// fallback used when SentinelUseDisconnectedReplicas is in effect.
+	disconnectedAddrs map[string]bool
+
+	// unhealthyReplicas holds the addresses of replicas which the live
+	// +sdown/+odown event stream (see handleSentinelEvent) has told us are
+	// down since the last poll. It's a subset of disconnectedAddrs that gets
+	// updated immediately on events rather than waiting for the next
+	// ensureClients poll, and is consulted by selectReplicaAddr so a
+	// just-downed replica isn't chosen again before the next poll catches up.
+	unhealthyReplicas map[string]bool
+
 	// We use a persistent PubSubConn here, so we don't need to do much after
 	// initialization. The pconn is only really kept around for closing
 	pconn   PubSubConn
@@ -126,13 +262,15 @@ func NewSentinel(ctx context.Context, primaryName string, sentinelAddrs []string
 	}
 
 	sc := &Sentinel{
-		proc:          proc.New(),
-		initAddrs:     sentinelAddrs,
-		name:          primaryName,
-		clients:       map[string]Client{},
-		sentinelAddrs: addrs,
-		pconnCh:       make(chan PubSubMessage, 1),
-		testEventCh:   make(chan string, 1),
+		proc:              proc.New(),
+		initAddrs:         sentinelAddrs,
+		name:              primaryName,
+		clients:           map[string]Client{},
+		sentinelAddrs:     addrs,
+		disconnectedAddrs: map[string]bool{},
+		unhealthyReplicas: map[string]bool{},
+		pconnCh:           make(chan PubSubMessage, 1),
+		testEventCh:       make(chan string, 1),
 	}
 
 	// If the given sentinelAddrs have AUTH/SELECT info encoded into them then
@@ -141,6 +279,10 @@ func NewSentinel(ctx context.Context, primaryName string, sentinelAddrs []string
 	sc.opts.cf = wrapDefaultConnFunc(sentinelAddrs[0])
 	defaultSentinelOpts := []SentinelOpt{
 		SentinelPoolFunc(DefaultClientFunc),
+		SentinelReplicaSelector(SelectRandom()),
+		SentinelPollInterval(defaultSentinelPollInterval),
+		// the default pool above isn't a caller-supplied one
+		func(so *sentinelOpts) { so.customPoolFunc = false },
 	}
 
 	for _, opt := range append(defaultSentinelOpts, opts...) {
@@ -152,6 +294,57 @@ func NewSentinel(ctx context.Context, primaryName string, sentinelAddrs []string
 		}
 	}
 
+	if sc.opts.authConflict {
+		return nil, errors.New("radix: SentinelAuth and SentinelAuthACL can't both be used")
+	}
+
+	// SentinelAuth/SentinelAuthACL only ever apply to sentinel connections,
+	// and only take effect if the user hasn't taken over dialing entirely via
+	// SentinelConnFunc.
+	if !sc.opts.customConnFunc && sc.opts.authOpt != nil {
+		sc.opts.cf = wrapDefaultConnFunc(sentinelAddrs[0], sc.opts.authOpt)
+	}
+
+	// SentinelClientName, on the other hand, applies regardless of how the
+	// sentinel connection was dialed.
+	if sc.opts.clientName != "" {
+		baseCF := sc.opts.cf
+		name := sc.opts.clientName
+		sc.opts.cf = func(ctx context.Context, network, addr string) (Conn, error) {
+			conn, err := baseCF(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			} else if err := conn.Do(ctx, Cmd(nil, "CLIENT", "SETNAME", name)); err != nil {
+				conn.Close()
+				return nil, fmt.Errorf("calling CLIENT SETNAME on sentinel connection: %w", err)
+			}
+			return conn, nil
+		}
+	}
+
+	// SentinelClientName also needs to name every connection a
+	// primary/replica pool opens, not just whichever one happens to serve a
+	// single one-shot command. If the pool is still the default one, rebuild
+	// it with a ConnFunc that does the naming at dial time, same as above.
+	// If the caller replaced it via SentinelPoolFunc we have no hook into its
+	// dialing, and client() falls back to naming just the first connection
+	// it hands out.
+	if sc.opts.clientName != "" && !sc.opts.customPoolFunc {
+		name := sc.opts.clientName
+		sc.opts.pf = func(ctx context.Context, network, addr string) (Client, error) {
+			return NewPool(ctx, network, addr, PoolConnFunc(func(ctx context.Context, network, addr string) (Conn, error) {
+				conn, err := Dial(ctx, network, addr)
+				if err != nil {
+					return nil, err
+				} else if err := conn.Do(ctx, Cmd(nil, "CLIENT", "SETNAME", name)); err != nil {
+					conn.Close()
+					return nil, fmt.Errorf("calling CLIENT SETNAME on %q: %w", addr, err)
+				}
+				return conn, nil
+			}))
+		}
+	}
+
 	// first thing is to retrieve the state and create a pool using the first
 	// connectable connection. This connection is only used during
 	// initialization, it gets closed right after
@@ -179,7 +372,14 @@ func NewSentinel(ctx context.Context, primaryName string, sentinelAddrs []string
 		return nil, err
 	}
 
-	sc.pconn.Subscribe(ctx, sc.pconnCh, "switch-master")
+	sc.pconn.Subscribe(ctx, sc.pconnCh,
+		"switch-master",
+		"+sdown", "-sdown",
+		"+odown", "-odown",
+		"+slave",
+		"+sentinel",
+		"+reboot",
+	)
 	sc.proc.Run(sc.spin)
 	return sc, nil
 }
@@ -219,26 +419,87 @@ func (sc *Sentinel) dialSentinel(ctx context.Context) (conn Conn, err error) {
 	return
 }
 
-// Do implements the method for the Client interface. It will perform the given
-// Action on the current primary.
+// Do implements the method for the Client interface. It will perform the
+// given Action on the current primary, unless SentinelReplicaOnly is in
+// effect, in which case it behaves like DoSecondary.
 func (sc *Sentinel) Do(ctx context.Context, a Action) error {
+	if sc.opts.replicaOnly {
+		return sc.DoSecondary(ctx, a)
+	}
 	return sc.proc.WithRLock(func() error {
 		return sc.clients[sc.primAddr].Do(ctx, a)
 	})
 }
 
 // DoSecondary implements the method for the Client interface. It will perform
-// the given Action on a random secondary, or the primary if no secondary is
-// available.
+// the given Action on a secondary chosen by the configured ReplicaSelector
+// (see SentinelReplicaSelector). If no healthy secondary is available and
+// SentinelUseDisconnectedReplicas is in effect, a disconnected replica is
+// used instead; otherwise the primary is used.
 //
 // For DoSecondary to work, replicas must be configured with replica-read-only
 // enabled, otherwise calls to DoSecondary may by rejected by the replica.
 func (sc *Sentinel) DoSecondary(ctx context.Context, a Action) error {
-	c, err := sc.client(ctx, "")
+	addr, err := sc.selectReplicaAddr()
 	if err != nil {
 		return err
 	}
-	return c.Do(ctx, a)
+
+	c, err := sc.client(ctx, addr)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err = c.Do(ctx, a)
+	if addr != "" {
+		sc.opts.replicaSelector.sample(addr, time.Since(start), err)
+	}
+	return err
+}
+
+// selectReplicaAddr returns the address of a replica to use, as chosen by the
+// configured ReplicaSelector, or "" (with a nil error) if the primary should
+// be used instead. If the selector can't find a healthy candidate and
+// SentinelUseDisconnectedReplicas is in effect, a disconnected replica's
+// address is returned as a last resort.
+func (sc *Sentinel) selectReplicaAddr() (string, error) {
+	var replicaAddrs, disconnectedAddrs []string
+	err := sc.proc.WithRLock(func() error {
+		replicaAddrs = make([]string, 0, len(sc.clients))
+		for addr := range sc.clients {
+			if addr != sc.primAddr && !sc.unhealthyReplicas[addr] && !sc.disconnectedAddrs[addr] {
+				replicaAddrs = append(replicaAddrs, addr)
+			}
+		}
+		disconnectedAddrs = make([]string, 0, len(sc.disconnectedAddrs))
+		for addr := range sc.disconnectedAddrs {
+			disconnectedAddrs = append(disconnectedAddrs, addr)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if addr := sc.opts.replicaSelector.selectReplica(replicaAddrs); addr != "" {
+		return addr, nil
+	}
+	if sc.opts.useDisconnectedReplicas && len(disconnectedAddrs) > 0 {
+		return disconnectedAddrs[rand.Intn(len(disconnectedAddrs))], nil
+	}
+	return "", nil
+}
+
+// ReplicaStats returns the latency statistics currently being tracked for
+// each replica, as observed by the configured ReplicaSelector. If the
+// configured selector doesn't track latency (e.g. SelectRandom or
+// SelectRoundRobin) the returned map will be empty.
+func (sc *Sentinel) ReplicaStats() map[string]ReplicaStat {
+	if ls, ok := sc.opts.replicaSelector.(*latencySelector); ok {
+		return ls.replicaStats()
+	}
+	return map[string]ReplicaStat{}
 }
 
 // Clients implements the method for the MultiClient interface. The returned map
@@ -273,18 +534,15 @@ func (sc *Sentinel) SentinelAddrs() ([]string, error) {
 	return sentAddrs, err
 }
 
+// client returns the Client for addr, creating one if necessary. An empty
+// addr means the primary's Client should be returned.
 func (sc *Sentinel) client(ctx context.Context, addr string) (Client, error) {
 	var client Client
 	err := sc.proc.WithRLock(func() error {
 		if addr == "" {
-			for addr, client = range sc.clients {
-				if addr != sc.primAddr {
-					break
-				}
-			}
-		}
-		if client == nil {
 			client = sc.clients[sc.primAddr]
+		} else {
+			client = sc.clients[addr]
 		}
 		return nil
 	})
@@ -304,6 +562,18 @@ func (sc *Sentinel) client(ctx context.Context, addr string) (Client, error) {
 		return nil, err
 	}
 
+	// For the default pool, NewSentinel already arranged for every
+	// connection to be named via PoolConnFunc. A caller-supplied ClientFunc
+	// gives us no hook into its dialing, so this is a best-effort fallback
+	// that only names whichever single connection happens to serve the
+	// first command on it.
+	if sc.opts.clientName != "" && sc.opts.customPoolFunc {
+		if err := newClient.Do(ctx, Cmd(nil, "CLIENT", "SETNAME", sc.opts.clientName)); err != nil {
+			newClient.Close()
+			return nil, fmt.Errorf("calling CLIENT SETNAME on %q: %w", addr, err)
+		}
+	}
+
 	// two routines might be requesting the same addr at the same time, and
 	// both create the client. The second one needs to make sure it closes its
 	// own pool when it sees the other got there first.
@@ -345,6 +615,148 @@ func sentinelMtoAddr(m map[string]string, cmd string) (string, error) {
 	return net.JoinHostPort(m["ip"], m["port"]), nil
 }
 
+// sentinelMIsDisconnected returns true if m's flags field (as returned by
+// SENTINEL SLAVES/SENTINELS) indicates the instance is currently unreachable.
+func sentinelMIsDisconnected(m map[string]string) bool {
+	for _, flag := range strings.Split(m["flags"], ",") {
+		switch flag {
+		case "s_down", "o_down", "disconnected":
+			return true
+		}
+	}
+	return false
+}
+
+// sentinelEventPayload holds the parsed fields of a message published by a
+// sentinel to one of the event channels innerSpin is subscribed to. The wire
+// format is space-separated: "<instance-type> <name> <ip> <port> [@
+// <master-name> <master-ip> <master-port>]", where the "@ ..." suffix is
+// omitted for events about the master itself.
+type sentinelEventPayload struct {
+	instanceType string
+	addr         string
+	masterName   string
+}
+
+func parseSentinelEventPayload(payload string) (sentinelEventPayload, bool) {
+	fields := strings.Fields(payload)
+	if len(fields) < 4 {
+		return sentinelEventPayload{}, false
+	}
+
+	p := sentinelEventPayload{
+		instanceType: fields[0],
+		addr:         net.JoinHostPort(fields[2], fields[3]),
+	}
+	for i, f := range fields {
+		if f == "@" && i+1 < len(fields) {
+			p.masterName = fields[i+1]
+			break
+		}
+	}
+	return p, true
+}
+
+// handleSentinelEvent applies whatever immediate bookkeeping update a
+// pubsub message from one of the event channels innerSpin is subscribed to
+// calls for, then traces it via SentinelTrace. Events about a primary/replica
+// set other than the one this Sentinel was created for are ignored, as are
+// any channels this Sentinel doesn't otherwise act on (e.g. +reboot) -- those
+// are only used for tracing.
+func (sc *Sentinel) handleSentinelEvent(ctx context.Context, msg PubSubMessage) {
+	p, ok := parseSentinelEventPayload(string(msg.Message))
+	masterName := p.masterName
+	if ok && masterName == "" {
+		// payloads with no "@" suffix name their master directly: as the
+		// first field on switch-master, as the second for a master event.
+		if msg.Channel == "switch-master" {
+			masterName = p.instanceType
+		} else if p.instanceType == "master" {
+			masterName = strings.Fields(string(msg.Message))[1]
+		}
+	}
+	if ok && masterName != "" && masterName != sc.name {
+		return
+	}
+
+	if ok {
+		switch msg.Channel {
+		case "+sdown", "+odown":
+			if p.instanceType == "slave" {
+				sc.markReplicaHealth(ctx, p.addr, false)
+			}
+		case "-sdown", "-odown":
+			if p.instanceType == "slave" {
+				sc.markReplicaHealth(ctx, p.addr, true)
+			}
+		case "+slave":
+			if p.instanceType == "slave" {
+				sc.addReplica(ctx, p.addr)
+			}
+		case "+sentinel":
+			_ = sc.proc.WithLock(func() error {
+				sc.sentinelAddrs[p.addr] = true
+				return nil
+			})
+		}
+	}
+
+	if sc.opts.st.SentinelEvent != nil {
+		sc.opts.st.SentinelEvent(trace.SentinelEvent{
+			Channel: msg.Channel,
+			Payload: msg.Message,
+		})
+	}
+}
+
+// addReplica brings a newly-announced replica into the topology immediately,
+// rather than waiting for the next ensureClients poll: it clears any stale
+// disconnected/unhealthy bookkeeping for addr and, via client(), dials and
+// registers a Client for it if one doesn't already exist.
+func (sc *Sentinel) addReplica(ctx context.Context, addr string) {
+	_ = sc.proc.WithLock(func() error {
+		delete(sc.unhealthyReplicas, addr)
+		delete(sc.disconnectedAddrs, addr)
+		return nil
+	})
+	if _, err := sc.client(ctx, addr); err != nil {
+		sc.err(fmt.Errorf("adding replica %q from +slave event: %w", addr, err))
+	}
+}
+
+// markReplicaHealth immediately marks addr as healthy or unhealthy, ahead of
+// the next ensureClients poll, so that selectReplicaAddr stops (or resumes)
+// considering it a candidate right away. Marking a replica healthy again
+// after it was only ever known as disconnected (i.e. it never had a Client
+// in sc.clients) also dials it via client(), so it's actually selectable
+// immediately rather than merely eligible on the next poll.
+func (sc *Sentinel) markReplicaHealth(ctx context.Context, addr string, healthy bool) {
+	if !healthy {
+		_ = sc.proc.WithLock(func() error {
+			sc.unhealthyReplicas[addr] = true
+			sc.disconnectedAddrs[addr] = true
+			return nil
+		})
+		return
+	}
+
+	var wasDisconnected bool
+	_ = sc.proc.WithLock(func() error {
+		delete(sc.unhealthyReplicas, addr)
+		if sc.disconnectedAddrs[addr] {
+			wasDisconnected = true
+			delete(sc.disconnectedAddrs, addr)
+		}
+		return nil
+	})
+
+	if wasDisconnected {
+		if _, err := sc.client(ctx, addr); err != nil {
+			sc.err(fmt.Errorf("recovering replica %q from -sdown/-odown event: %w", addr, err))
+		}
+	}
+}
+
 // given a connection to a sentinel, ensures that the Clients currently being
 // held agrees with what the sentinel thinks they should be
 func (sc *Sentinel) ensureClients(ctx context.Context, conn Conn) error {
@@ -363,15 +775,26 @@ func (sc *Sentinel) ensureClients(ctx context.Context, conn Conn) error {
 	}
 
 	newClients := map[string]Client{newPrimAddr: nil}
+	newDisconnectedAddrs := map[string]bool{}
 	for _, secM := range secMM {
 		newSecAddr, err := sentinelMtoAddr(secM, "SENTINEL SLAVES")
 		if err != nil {
 			return err
 		}
-		newClients[newSecAddr] = nil
+		// Only split disconnected secondaries out of the normal candidate set
+		// when SentinelUseDisconnectedReplicas is in effect; otherwise keep
+		// the longstanding behavior of treating every secondary the
+		// sentinels report as a regular candidate, transient s_down/o_down
+		// included, so enabling this series doesn't change default behavior
+		// for existing Sentinel users.
+		if sc.opts.useDisconnectedReplicas && sentinelMIsDisconnected(secM) {
+			newDisconnectedAddrs[newSecAddr] = true
+		} else {
+			newClients[newSecAddr] = nil
+		}
 	}
 
-	// ensure all current clients exist
+	// ensure all current (connected) clients exist
 	newTraceNodes := map[string]trace.SentinelNodeInfo{}
 	for addr := range newClients {
 		client, err := sc.client(ctx, addr)
@@ -384,6 +807,12 @@ func (sc *Sentinel) ensureClients(ctx context.Context, conn Conn) error {
 			IsPrimary: addr == newPrimAddr,
 		}
 	}
+	for addr := range newDisconnectedAddrs {
+		newTraceNodes[addr] = trace.SentinelNodeInfo{
+			Addr:           addr,
+			IsDisconnected: true,
+		}
+	}
 
 	var toClose []Client
 	prevTraceNodes := map[string]trace.SentinelNodeInfo{}
@@ -403,9 +832,25 @@ func (sc *Sentinel) ensureClients(ctx context.Context, conn Conn) error {
 				toClose = append(toClose, client)
 			}
 		}
+		for addr := range sc.disconnectedAddrs {
+			prevTraceNodes[addr] = trace.SentinelNodeInfo{
+				Addr:           addr,
+				IsDisconnected: true,
+			}
+		}
 
 		sc.primAddr = newPrimAddr
 		sc.clients = newClients
+		sc.disconnectedAddrs = newDisconnectedAddrs
+
+		// the poll is authoritative: drop any unhealthy mark for a replica
+		// which this poll didn't itself report as down, so a stale event
+		// doesn't keep a recovered (or removed) replica excluded forever.
+		for addr := range sc.unhealthyReplicas {
+			if !newDisconnectedAddrs[addr] {
+				delete(sc.unhealthyReplicas, addr)
+			}
+		}
 
 		return nil
 	})
@@ -491,6 +936,11 @@ func (sc *Sentinel) spin(ctx context.Context) {
 	}
 }
 
+// replicaLatencyProbeInterval is the base interval on which innerSpin PINGs
+// each known replica to sample its RTT for SelectByLatency. A bit of jitter is
+// added on top so that many Sentinel instances don't all probe in lockstep.
+const replicaLatencyProbeInterval = 10 * time.Second
+
 // makes connection to an address in sc.addrs and handles
 // the sentinel until that connection goes bad.
 //
@@ -500,6 +950,8 @@ func (sc *Sentinel) spin(ctx context.Context) {
 // * Periodically re-ensuring that the list of sentinel addresses is up-to-date
 // * Periodically re-checking the current primary, in case the switch-master was
 //   missed somehow
+// * Periodically PINGing known replicas to sample their RTT, for use by
+//   SelectByLatency -- only when that's the configured ReplicaSelector
 func (sc *Sentinel) innerSpin(ctx context.Context) error {
 	conn, err := sc.dialSentinel(ctx)
 	if err != nil {
@@ -507,9 +959,22 @@ func (sc *Sentinel) innerSpin(ctx context.Context) error {
 	}
 	defer conn.Close()
 
-	tick := time.NewTicker(5 * time.Second)
+	tick := time.NewTicker(sc.opts.pollInterval)
 	defer tick.Stop()
 
+	// Only probe replica latency when the configured selector actually
+	// consumes the samples (see ReplicaStats); for SelectRandom/
+	// SelectRoundRobin, sample is a no-op, so leaving pingTickC nil skips the
+	// ticker case below entirely and avoids PINGing every replica for
+	// nothing.
+	var pingTickC <-chan time.Time
+	if _, ok := sc.opts.replicaSelector.(*latencySelector); ok {
+		pingJitter := time.Duration(rand.Int63n(int64(2 * time.Second)))
+		pingTick := time.NewTicker(replicaLatencyProbeInterval + pingJitter)
+		defer pingTick.Stop()
+		pingTickC = pingTick.C
+	}
+
 	var switchMaster bool
 	for {
 		err := func() error {
@@ -540,10 +1005,16 @@ func (sc *Sentinel) innerSpin(ctx context.Context) error {
 		select {
 		case <-tick.C:
 			// loop
-		case <-sc.pconnCh:
-			switchMaster = true
-			if waitFor := atomic.SwapUint32(&sc.testSleepBeforeSwitch, 0); waitFor > 0 {
-				time.Sleep(time.Duration(waitFor) * time.Millisecond)
+		case <-pingTickC:
+			sc.probeReplicaLatencies(ctx)
+			sc.testEvent("replica latency probe completed")
+		case msg := <-sc.pconnCh:
+			sc.handleSentinelEvent(ctx, msg)
+			if msg.Channel == "switch-master" {
+				switchMaster = true
+				if waitFor := atomic.SwapUint32(&sc.testSleepBeforeSwitch, 0); waitFor > 0 {
+					time.Sleep(time.Duration(waitFor) * time.Millisecond)
+				}
 			}
 			// loop
 		case <-ctx.Done():
@@ -552,6 +1023,30 @@ func (sc *Sentinel) innerSpin(ctx context.Context) error {
 	}
 }
 
+// probeReplicaLatencies issues a lightweight PING against every currently
+// known replica and feeds the measured RTT (or error) into the configured
+// ReplicaSelector.
+func (sc *Sentinel) probeReplicaLatencies(ctx context.Context) {
+	var replicas map[string]Client
+	_ = sc.proc.WithRLock(func() error {
+		replicas = make(map[string]Client, len(sc.clients))
+		for addr, client := range sc.clients {
+			if addr != sc.primAddr {
+				replicas[addr] = client
+			}
+		}
+		return nil
+	})
+
+	for addr, client := range replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		start := time.Now()
+		err := client.Do(pingCtx, Cmd(nil, "PING"))
+		cancel()
+		sc.opts.replicaSelector.sample(addr, time.Since(start), err)
+	}
+}
+
 func (sc *Sentinel) forceMasterSwitch(waitFor time.Duration) {
 	// can not use waitFor.Milliseconds() here since it was only introduced in Go 1.13 and we still support 1.12
 	atomic.StoreUint32(&sc.testSleepBeforeSwitch, uint32(waitFor.Nanoseconds()/1e6))
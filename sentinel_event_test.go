@@ -0,0 +1,182 @@
+package radix
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseSentinelEventPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    sentinelEventPayload
+		wantOk  bool
+	}{
+		{
+			name:    "master event, no @ suffix",
+			payload: "master mymaster 127.0.0.1 6379",
+			want: sentinelEventPayload{
+				instanceType: "master",
+				addr:         "127.0.0.1:6379",
+			},
+			wantOk: true,
+		},
+		{
+			name:    "slave event, with @ master suffix",
+			payload: "slave 127.0.0.1:6380 127.0.0.1 6380 @ mymaster 127.0.0.1 6379",
+			want: sentinelEventPayload{
+				instanceType: "slave",
+				addr:         "127.0.0.1:6380",
+				masterName:   "mymaster",
+			},
+			wantOk: true,
+		},
+		{
+			name:    "sentinel event, with @ master suffix",
+			payload: "sentinel abc123 127.0.0.1 26380 @ mymaster 127.0.0.1 6379",
+			want: sentinelEventPayload{
+				instanceType: "sentinel",
+				addr:         "127.0.0.1:26380",
+				masterName:   "mymaster",
+			},
+			wantOk: true,
+		},
+		{
+			name:    "trailing @ with nothing after it is ignored",
+			payload: "slave 127.0.0.1:6380 127.0.0.1 6380 @",
+			want: sentinelEventPayload{
+				instanceType: "slave",
+				addr:         "127.0.0.1:6380",
+			},
+			wantOk: true,
+		},
+		{
+			name:    "too few fields",
+			payload: "master mymaster 127.0.0.1",
+			wantOk:  false,
+		},
+		{
+			name:    "empty payload",
+			payload: "",
+			wantOk:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := parseSentinelEventPayload(test.payload)
+			if ok != test.wantOk {
+				t.Fatalf("parseSentinelEventPayload(%q) ok = %v, want %v", test.payload, ok, test.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if got != test.want {
+				t.Errorf("parseSentinelEventPayload(%q) = %+v, want %+v", test.payload, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSentinelMIsDisconnected(t *testing.T) {
+	tests := []struct {
+		flags string
+		want  bool
+	}{
+		{flags: "slave", want: false},
+		{flags: "s_down,slave", want: true},
+		{flags: "slave,o_down", want: true},
+		{flags: "disconnected", want: true},
+		{flags: "", want: false},
+	}
+
+	for _, test := range tests {
+		m := map[string]string{"flags": test.flags}
+		if got := sentinelMIsDisconnected(m); got != test.want {
+			t.Errorf("sentinelMIsDisconnected(%q) = %v, want %v", test.flags, got, test.want)
+		}
+	}
+}
+
+// TestHandleSentinelEventTopologyTransitions exercises the immediate
+// bookkeeping handleSentinelEvent performs for +slave (a replica joining)
+// and +sdown/-sdown (a replica going down and recovering), asserting that
+// each flips the replica candidate set right away rather than waiting for
+// the next ensureClients poll.
+func TestHandleSentinelEventTopologyTransitions(t *testing.T) {
+	ctx := context.Background()
+	sc := newTestSentinel()
+	sc.opts.replicaSelector = SelectRandom()
+	sc.opts.pf = func(ctx context.Context, network, addr string) (Client, error) {
+		return NewStubConn("", "", func([]string) interface{} { return nil }), nil
+	}
+
+	const replicaAddr = "127.0.0.1:6380"
+
+	// +slave brings a brand new replica into the topology immediately.
+	sc.handleSentinelEvent(ctx, PubSubMessage{
+		Channel: "+slave",
+		Message: []byte("slave replicaY 127.0.0.1 6380"),
+	})
+	if _, ok := sc.clients[replicaAddr]; !ok {
+		t.Fatalf("clients = %v, want %q present after +slave", sc.clients, replicaAddr)
+	}
+	if addr, _ := sc.selectReplicaAddr(); addr != replicaAddr {
+		t.Errorf("selectReplicaAddr() = %q, want %q right after +slave", addr, replicaAddr)
+	}
+
+	// +sdown immediately excludes it from the candidate set.
+	sc.handleSentinelEvent(ctx, PubSubMessage{
+		Channel: "+sdown",
+		Message: []byte("slave replicaY 127.0.0.1 6380"),
+	})
+	if !sc.unhealthyReplicas[replicaAddr] || !sc.disconnectedAddrs[replicaAddr] {
+		t.Fatalf("unhealthyReplicas/disconnectedAddrs = %v/%v, want %q marked in both after +sdown",
+			sc.unhealthyReplicas, sc.disconnectedAddrs, replicaAddr)
+	}
+	if addr, _ := sc.selectReplicaAddr(); addr == replicaAddr {
+		t.Errorf("selectReplicaAddr() = %q, want it excluded after +sdown", addr)
+	}
+
+	// -sdown immediately makes it selectable again.
+	sc.handleSentinelEvent(ctx, PubSubMessage{
+		Channel: "-sdown",
+		Message: []byte("slave replicaY 127.0.0.1 6380"),
+	})
+	if sc.unhealthyReplicas[replicaAddr] || sc.disconnectedAddrs[replicaAddr] {
+		t.Fatalf("unhealthyReplicas/disconnectedAddrs = %v/%v, want %q cleared after -sdown",
+			sc.unhealthyReplicas, sc.disconnectedAddrs, replicaAddr)
+	}
+	if addr, _ := sc.selectReplicaAddr(); addr != replicaAddr {
+		t.Errorf("selectReplicaAddr() = %q, want %q selectable again right after -sdown", addr, replicaAddr)
+	}
+}
+
+// TestHandleSentinelEventRecoversDisconnectedOnlyReplica covers the case
+// where a replica was only ever known through disconnectedAddrs (e.g. it was
+// already down at the last ensureClients poll, so it never got a Client in
+// sc.clients): recovery must dial and register it, not just clear the flag.
+func TestHandleSentinelEventRecoversDisconnectedOnlyReplica(t *testing.T) {
+	ctx := context.Background()
+	sc := newTestSentinel()
+	sc.opts.replicaSelector = SelectRandom()
+	sc.opts.pf = func(ctx context.Context, network, addr string) (Client, error) {
+		return NewStubConn("", "", func([]string) interface{} { return nil }), nil
+	}
+
+	const replicaAddr = "127.0.0.1:6381"
+	sc.disconnectedAddrs[replicaAddr] = true
+	sc.unhealthyReplicas[replicaAddr] = true
+
+	sc.handleSentinelEvent(ctx, PubSubMessage{
+		Channel: "-odown",
+		Message: []byte("slave replicaZ 127.0.0.1 6381"),
+	})
+
+	if _, ok := sc.clients[replicaAddr]; !ok {
+		t.Fatalf("clients = %v, want %q dialed and registered on recovery", sc.clients, replicaAddr)
+	}
+	if sc.unhealthyReplicas[replicaAddr] || sc.disconnectedAddrs[replicaAddr] {
+		t.Errorf("unhealthyReplicas/disconnectedAddrs still mark %q after recovery", replicaAddr)
+	}
+}
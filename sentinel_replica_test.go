@@ -0,0 +1,76 @@
+package radix
+
+import (
+	"testing"
+
+	"github.com/mediocregopher/radix/v4/internal/proc"
+)
+
+func newTestSentinel() *Sentinel {
+	return &Sentinel{
+		proc:              proc.New(),
+		name:              "mymaster",
+		primAddr:          "primary",
+		clients:           map[string]Client{"primary": nil},
+		sentinelAddrs:     map[string]bool{},
+		disconnectedAddrs: map[string]bool{},
+		unhealthyReplicas: map[string]bool{},
+	}
+}
+
+func TestSelectReplicaAddrDisconnectedFallback(t *testing.T) {
+	sc := newTestSentinel()
+	sc.opts.replicaSelector = SelectRandom()
+	sc.disconnectedAddrs["replicaX"] = true
+
+	// with SentinelUseDisconnectedReplicas off, a disconnected-only replica
+	// set must not be used -- the primary is used instead.
+	addr, err := sc.selectReplicaAddr()
+	if err != nil {
+		t.Fatalf("selectReplicaAddr() err = %v", err)
+	}
+	if addr != "" {
+		t.Errorf("selectReplicaAddr() = %q, want empty (no healthy replica, fallback disabled)", addr)
+	}
+
+	// with it on, the disconnected replica is used as a last resort.
+	sc.opts.useDisconnectedReplicas = true
+	addr, err = sc.selectReplicaAddr()
+	if err != nil {
+		t.Fatalf("selectReplicaAddr() err = %v", err)
+	}
+	if addr != "replicaX" {
+		t.Errorf("selectReplicaAddr() = %q, want %q", addr, "replicaX")
+	}
+}
+
+func TestSelectReplicaAddrExcludesCachedDisconnected(t *testing.T) {
+	sc := newTestSentinel()
+	sc.opts.replicaSelector = SelectRandom()
+	sc.opts.useDisconnectedReplicas = true
+
+	// replicaX has both a cached Client (from an earlier fallback dial) and
+	// a disconnectedAddrs entry -- it must stay excluded from the normal
+	// candidate set rather than being promoted to a first-class healthy
+	// replica just because a Client now exists for it.
+	sc.clients["replicaX"] = nil
+	sc.disconnectedAddrs["replicaX"] = true
+
+	addr, err := sc.selectReplicaAddr()
+	if err != nil {
+		t.Fatalf("selectReplicaAddr() err = %v", err)
+	}
+	if addr != "replicaX" {
+		t.Errorf("selectReplicaAddr() = %q, want %q (disconnected fallback, not a normal pick)", addr, "replicaX")
+	}
+
+	// once it's no longer disconnected, it becomes a normal candidate.
+	delete(sc.disconnectedAddrs, "replicaX")
+	addr, err = sc.selectReplicaAddr()
+	if err != nil {
+		t.Fatalf("selectReplicaAddr() err = %v", err)
+	}
+	if addr != "replicaX" {
+		t.Errorf("selectReplicaAddr() = %q, want %q (now a normal candidate)", addr, "replicaX")
+	}
+}
@@ -0,0 +1,198 @@
+package radix
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/mediocregopher/radix/v4/resp"
+)
+
+// ReplicaStat describes the latency Sentinel has observed for a single
+// replica, as tracked when SelectByLatency is in use.
+type ReplicaStat struct {
+	Addr        string
+	EWMA        time.Duration
+	Unreachable bool
+}
+
+// ReplicaSelector chooses which replica address a Sentinel should route a
+// DoSecondary call to (or, with SentinelReplicaOnly, a Do call). Implementations
+// must be safe for use by multiple goroutines at once.
+//
+// ReplicaSelector isn't intended to be implemented outside of this package;
+// use SelectRandom, SelectRoundRobin, or SelectByLatency to construct one.
+type ReplicaSelector interface {
+	// selectReplica returns the address, out of addrs, which should service
+	// the next call, or "" if none of addrs is currently suitable.
+	selectReplica(addrs []string) string
+
+	// sample is called opportunistically any time a latency measurement for
+	// addr becomes available, whether from a Do/DoSecondary call completing or
+	// from an internal PING probe. err is set if the measurement failed (e.g.
+	// the PING timed out), in which case d is meaningless.
+	sample(addr string, d time.Duration, err error)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+type randomSelector struct{}
+
+// SelectRandom returns a ReplicaSelector which picks a uniformly random
+// replica, out of the currently known set, for every call.
+func SelectRandom() ReplicaSelector {
+	return randomSelector{}
+}
+
+func (randomSelector) selectReplica(addrs []string) string {
+	if len(addrs) == 0 {
+		return ""
+	}
+	return addrs[rand.Intn(len(addrs))]
+}
+
+func (randomSelector) sample(string, time.Duration, error) {}
+
+////////////////////////////////////////////////////////////////////////////////
+
+type roundRobinSelector struct {
+	l    sync.Mutex
+	next uint64
+}
+
+// SelectRoundRobin returns a ReplicaSelector which cycles through the
+// currently known replicas in turn.
+func SelectRoundRobin() ReplicaSelector {
+	return &roundRobinSelector{}
+}
+
+func (rr *roundRobinSelector) selectReplica(addrs []string) string {
+	if len(addrs) == 0 {
+		return ""
+	}
+	rr.l.Lock()
+	i := rr.next % uint64(len(addrs))
+	rr.next++
+	rr.l.Unlock()
+	return addrs[i]
+}
+
+func (*roundRobinSelector) sample(string, time.Duration, error) {}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// defaultLatencyEWMAAlpha is the weight given to each new RTT sample when
+// updating a replica's EWMA. Lower values smooth out transient spikes at the
+// cost of reacting to real latency changes more slowly.
+const defaultLatencyEWMAAlpha = 0.2
+
+type latencyStat struct {
+	ewma        time.Duration
+	unreachable bool
+}
+
+type latencySelector struct {
+	l     sync.Mutex
+	alpha float64
+	stats map[string]*latencyStat
+}
+
+// SelectByLatency returns a ReplicaSelector which tracks an
+// exponentially-weighted moving average RTT for each replica and always
+// picks the one with the lowest EWMA, breaking ties randomly. RTT samples
+// come both from a periodic PING probe and opportunistically from completed
+// Do/DoSecondary calls. Replicas whose most recent sample failed are skipped
+// until a subsequent sample succeeds.
+func SelectByLatency() ReplicaSelector {
+	return &latencySelector{
+		alpha: defaultLatencyEWMAAlpha,
+		stats: map[string]*latencyStat{},
+	}
+}
+
+func (ls *latencySelector) sample(addr string, d time.Duration, err error) {
+	ls.l.Lock()
+	defer ls.l.Unlock()
+
+	st, ok := ls.stats[addr]
+	if !ok {
+		st = new(latencyStat)
+		ls.stats[addr] = st
+	}
+
+	// An error wrapped in resp.ErrConnUsable is an ordinary application-level
+	// reply (WRONGTYPE, a Lua error, a MOVED/readonly rejection, etc.) -- the
+	// connection itself is fine, so addr was reached, it just rejected this
+	// particular command. Only a transport-level failure (e.g. a timed out
+	// or reset connection) means addr is actually unreachable.
+	if err != nil && errors.As(err, new(resp.ErrConnUsable)) {
+		st.unreachable = false
+		return
+	} else if err != nil {
+		st.unreachable = true
+		return
+	}
+
+	st.unreachable = false
+	if st.ewma == 0 {
+		st.ewma = d
+	} else {
+		st.ewma = time.Duration(ls.alpha*float64(d) + (1-ls.alpha)*float64(st.ewma))
+	}
+}
+
+func (ls *latencySelector) selectReplica(addrs []string) string {
+	ls.l.Lock()
+	defer ls.l.Unlock()
+
+	// Replicas with no sample yet have a zero EWMA, which must not be
+	// treated as "fastest" -- otherwise a replica that just joined, or any
+	// replica before the first latency probe runs, would win every race and
+	// monopolize traffic until it happens to get sampled. Keep them in a
+	// separate bucket, used only if nothing has been sampled at all yet.
+	var best, unsampled []string
+	bestEWMA := time.Duration(-1)
+	for _, addr := range addrs {
+		st := ls.stats[addr]
+		if st != nil && st.unreachable {
+			continue
+		} else if st == nil || st.ewma == 0 {
+			unsampled = append(unsampled, addr)
+			continue
+		}
+
+		switch {
+		case bestEWMA < 0 || st.ewma < bestEWMA:
+			bestEWMA = st.ewma
+			best = []string{addr}
+		case st.ewma == bestEWMA:
+			best = append(best, addr)
+		}
+	}
+
+	if len(best) > 0 {
+		return best[rand.Intn(len(best))]
+	}
+	if len(unsampled) > 0 {
+		return unsampled[rand.Intn(len(unsampled))]
+	}
+	return ""
+}
+
+// replicaStats returns a point-in-time snapshot of the latency statistics
+// being tracked for each replica which has been sampled so far.
+func (ls *latencySelector) replicaStats() map[string]ReplicaStat {
+	ls.l.Lock()
+	defer ls.l.Unlock()
+
+	m := make(map[string]ReplicaStat, len(ls.stats))
+	for addr, st := range ls.stats {
+		m[addr] = ReplicaStat{
+			Addr:        addr,
+			EWMA:        st.ewma,
+			Unreachable: st.unreachable,
+		}
+	}
+	return m
+}
@@ -0,0 +1,110 @@
+package radix
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSelectRandom(t *testing.T) {
+	rs := SelectRandom()
+	if got := rs.selectReplica(nil); got != "" {
+		t.Fatalf("selectReplica(nil) = %q, want empty", got)
+	}
+
+	addrs := []string{"a", "b", "c"}
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		got := rs.selectReplica(addrs)
+		if !contains(addrs, got) {
+			t.Fatalf("selectReplica returned %q, not in %v", got, addrs)
+		}
+		seen[got] = true
+	}
+	if len(seen) != len(addrs) {
+		t.Errorf("selectReplica only ever returned %v over 100 calls, expected all of %v eventually", seen, addrs)
+	}
+}
+
+func TestSelectRoundRobin(t *testing.T) {
+	rs := SelectRoundRobin()
+	if got := rs.selectReplica(nil); got != "" {
+		t.Fatalf("selectReplica(nil) = %q, want empty", got)
+	}
+
+	addrs := []string{"a", "b", "c"}
+	var got []string
+	for i := 0; i < len(addrs)*2; i++ {
+		got = append(got, rs.selectReplica(addrs))
+	}
+	want := []string{"a", "b", "c", "a", "b", "c"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("selectReplica sequence = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSelectByLatency(t *testing.T) {
+	rs := SelectByLatency()
+	addrs := []string{"a", "b", "c"}
+
+	// with no samples yet, every replica is unsampled, so any of them is a
+	// valid (random) pick
+	if got := rs.selectReplica(addrs); !contains(addrs, got) {
+		t.Fatalf("selectReplica with no samples = %q, not in %v", got, addrs)
+	}
+
+	rs.sample("a", 50*time.Millisecond, nil)
+	rs.sample("b", 10*time.Millisecond, nil)
+	rs.sample("c", 100*time.Millisecond, nil)
+	if got := rs.selectReplica(addrs); got != "b" {
+		t.Errorf("selectReplica = %q, want %q (lowest EWMA)", got, "b")
+	}
+
+	// an unreachable replica is skipped even if it had the best EWMA
+	rs.sample("b", 0, errors.New("dial timeout"))
+	if got := rs.selectReplica(addrs); got != "a" {
+		t.Errorf("selectReplica after b becomes unreachable = %q, want %q", got, "a")
+	}
+
+	// a later successful sample makes it eligible again
+	rs.sample("b", 5*time.Millisecond, nil)
+	if got := rs.selectReplica(addrs); got != "b" {
+		t.Errorf("selectReplica after b recovers = %q, want %q", got, "b")
+	}
+
+	// if every address is unreachable, there's no candidate to return
+	rs.sample("a", 0, errors.New("x"))
+	rs.sample("b", 0, errors.New("x"))
+	rs.sample("c", 0, errors.New("x"))
+	if got := rs.selectReplica(addrs); got != "" {
+		t.Errorf("selectReplica with all unreachable = %q, want empty", got)
+	}
+}
+
+func TestLatencySelectorReplicaStats(t *testing.T) {
+	ls := SelectByLatency().(*latencySelector)
+	ls.sample("a", 20*time.Millisecond, nil)
+	ls.sample("b", 0, errors.New("x"))
+
+	stats := ls.replicaStats()
+	if len(stats) != 2 {
+		t.Fatalf("replicaStats() = %v, want 2 entries", stats)
+	}
+	if stats["a"].Unreachable || stats["a"].EWMA != 20*time.Millisecond {
+		t.Errorf("replicaStats()[a] = %+v, want EWMA=20ms, Unreachable=false", stats["a"])
+	}
+	if !stats["b"].Unreachable {
+		t.Errorf("replicaStats()[b] = %+v, want Unreachable=true", stats["b"])
+	}
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}